@@ -0,0 +1,41 @@
+package dropy
+
+import (
+	"net/http"
+
+	"github.com/tj/go-dropbox"
+	"golang.org/x/oauth2"
+)
+
+// tokenURL is Dropbox's OAuth2 token endpoint, used to refresh expired
+// access tokens.
+const tokenURL = "https://api.dropbox.com/oauth2/token"
+
+// NewWithRefreshToken builds a Client whose HTTP transport automatically
+// refreshes its access token via Dropbox's OAuth2 token endpoint, using
+// refreshToken. Dropbox has deprecated long-lived access tokens, so this is
+// the recommended way to construct a Client for any long-running program.
+func NewWithRefreshToken(appKey, appSecret, refreshToken string) (*Client, error) {
+	conf := &oauth2.Config{
+		ClientID:     appKey,
+		ClientSecret: appSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL: tokenURL,
+		},
+	}
+
+	token := &oauth2.Token{RefreshToken: refreshToken}
+	return NewWithTokenSource(conf.TokenSource(oauth2.NoContext, token)), nil
+}
+
+// NewWithTokenSource builds a Client whose HTTP transport pulls access
+// tokens from ts, refreshing them as needed. Use this to plug in a token
+// source of your own, such as one backed by a refresh token obtained from a
+// prior interactive OAuth2 flow.
+func NewWithTokenSource(ts oauth2.TokenSource) *Client {
+	return New(dropbox.New(&dropbox.Config{
+		Client: &http.Client{
+			Transport: &oauth2.Transport{Source: ts},
+		},
+	}))
+}
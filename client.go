@@ -2,12 +2,16 @@
 package dropy
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"time"
 
 	"github.com/tj/go-dropbox"
+	"golang.org/x/sync/errgroup"
 )
 
 // Client wraps dropbox.Client to provide higher level sugar.
@@ -229,6 +233,10 @@ func (c *Client) Upload(path string, r io.Reader) error {
 
 const defaultChunkSize = 125e6
 
+// maxConcurrency caps the number of chunks uploaded in parallel, regardless
+// of what the caller requests.
+const maxConcurrency = 8
+
 // UploadSessionInput request input.
 type UploadSessionOptions struct {
 	//When Size is known, UploadSession can prevent a superfluous request.
@@ -236,6 +244,15 @@ type UploadSessionOptions struct {
 	Size int64
 	//ChunkSize is the number of bytes to upload in each call to append (defaults to 125MB).
 	ChunkSize int64
+	//Concurrency is the number of UploadSessionAppend calls to run in parallel.
+	//Chunks are still read from Commit.Reader sequentially, in order, so that
+	//each worker gets a precomputed, gap-free cursor offset. Values <= 1
+	//preserve the original sequential behavior. Capped at maxConcurrency.
+	Concurrency int
+	//OnProgress, when set, is called after every chunk uploaded by
+	//UploadSessionResume with the SessionState as of that chunk, so callers
+	//can checkpoint it to disk.
+	OnProgress func(SessionState)
 	//Commit information for uploaded file
 	Commit dropbox.UploadInput
 }
@@ -271,6 +288,9 @@ func (c *Client) UploadSessionOptions(opts UploadSessionOptions) (info os.FileIn
 	} else if opts.ChunkSize > 150e6 {
 		opts.ChunkSize = 150e6 //cap at 150MB
 	}
+	if opts.Concurrency > maxConcurrency {
+		opts.Concurrency = maxConcurrency
+	}
 	//upload session not required, use regular upload
 	if opts.Size > 0 && opts.ChunkSize > opts.Size && opts.Size < 150e6 {
 		out, err := c.Files.Upload(&opts.Commit)
@@ -279,6 +299,9 @@ func (c *Client) UploadSessionOptions(opts UploadSessionOptions) (info os.FileIn
 		}
 		return &FileInfo{&out.Metadata}, nil
 	}
+	if opts.Concurrency > 1 {
+		return c.uploadSessionConcurrent(opts)
+	}
 	//prepare chunk-sized-reader
 	lr := &io.LimitedReader{R: opts.Commit.Reader, N: opts.ChunkSize}
 	//start
@@ -316,3 +339,94 @@ func (c *Client) UploadSessionOptions(opts UploadSessionOptions) (info os.FileIn
 	}
 	return &FileInfo{&fin.UploadOutput.Metadata}, nil
 }
+
+// concurrentChunkMultiple is the block size Dropbox requires every
+// non-final chunk of a concurrent upload session to be a multiple of.
+const concurrentChunkMultiple = 4 * 1024 * 1024
+
+// uploadSessionConcurrent is the opts.Concurrency > 1 path of
+// UploadSessionOptions. Dropbox only allows racing, out-of-order
+// UploadSessionAppend calls against a session opened with
+// session_type=concurrent, and requires every non-final chunk to be a
+// multiple of 4 MiB -- only UploadSessionFinish may carry a short final
+// block; a plain (sequential) session rejects concurrent appends with
+// incorrect_offset. So, unlike the sequential path, the session is started
+// with a data-less call, every full-sized chunk -- including the first --
+// is read into its own buffer and dispatched to the worker pool, and the
+// final, possibly-short chunk is held back and handed to
+// UploadSessionFinish instead of being appended. The first append error
+// cancels the remaining dispatch via the errgroup's context.
+//
+// This assumes the imported github.com/tj/go-dropbox exposes
+// UploadSessionStartInput.SessionType and dropbox.UploadSessionTypeConcurrent;
+// without that, Dropbox's API has no way to open a concurrent session and
+// this path cannot work.
+func (c *Client) uploadSessionConcurrent(opts UploadSessionOptions) (os.FileInfo, error) {
+	if opts.ChunkSize%concurrentChunkMultiple != 0 {
+		return nil, fmt.Errorf("dropy: ChunkSize must be a multiple of %d bytes for concurrent upload sessions", concurrentChunkMultiple)
+	}
+
+	start, err := c.Files.UploadSessionStart(&dropbox.UploadSessionStartInput{
+		SessionType: dropbox.UploadSessionTypeConcurrent,
+		Reader:      bytes.NewReader(nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID := start.UploadSessionCursor.SessionID
+	offset := int64(0)
+	var final []byte
+
+	g, ctx := errgroup.WithContext(context.Background())
+	sem := make(chan struct{}, opts.Concurrency)
+
+	//read and dispatch full-sized chunks in order, until the reader is
+	//exhausted or a worker has already failed; the last, possibly-short
+	//chunk is never appended -- it's held back for UploadSessionFinish
+	for ctx.Err() == nil {
+		buf := make([]byte, opts.ChunkSize)
+		n, rerr := io.ReadFull(opts.Commit.Reader, buf)
+		if n == 0 {
+			break
+		}
+		buf = buf[:n]
+
+		isFinal := rerr == io.ErrUnexpectedEOF || rerr == io.EOF || int64(n) < opts.ChunkSize ||
+			(opts.Size > 0 && offset+int64(n) >= opts.Size)
+		if isFinal {
+			final = buf
+			break
+		}
+
+		curs := dropbox.UploadSessionCursor{SessionID: sessionID, Offset: offset}
+		offset += int64(n)
+
+		sem <- struct{}{}
+
+		g.Go(func() error {
+			defer func() { <-sem }()
+			return c.Files.UploadSessionAppend(&dropbox.UploadSessionAppendInput{
+				Cursor: curs,
+				Reader: bytes.NewReader(buf),
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	commit := opts.Commit
+	commit.Reader = bytes.NewReader(final)
+
+	curs := dropbox.UploadSessionCursor{SessionID: sessionID, Offset: offset}
+	fin, err := c.Files.UploadSessionFinish(&dropbox.UploadSessionFinishInput{
+		Cursor: curs,
+		Commit: commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{&fin.UploadOutput.Metadata}, nil
+}
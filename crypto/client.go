@@ -0,0 +1,154 @@
+package crypto
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/NightMan-1/go-dropy"
+)
+
+// EncryptedClient wraps a *dropy.Client so that Upload and UploadSession
+// transparently encrypt their input, and Download, Read and Open
+// transparently decrypt their output, using a master key supplied by the
+// caller. See GenerateKey to create one.
+type EncryptedClient struct {
+	*dropy.Client
+	key []byte
+}
+
+// NewEncryptedClient wraps c with an EncryptedClient that encrypts and
+// decrypts using key.
+func NewEncryptedClient(c *dropy.Client, key []byte) *EncryptedClient {
+	return &EncryptedClient{
+		Client: c,
+		key:    key,
+	}
+}
+
+// Upload encrypts r and uploads the ciphertext to path.
+func (c *EncryptedClient) Upload(path string, r io.Reader) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		ew, err := NewEncryptedWriter(pw, c.key)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(ew, r); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(ew.Close())
+	}()
+
+	return c.Client.Upload(path, pr)
+}
+
+// UploadSession encrypts r and uploads the ciphertext to path using an
+// internally managed upload session (see dropy.Client.UploadSession).
+func (c *EncryptedClient) UploadSession(path string, r io.Reader) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		ew, err := NewEncryptedWriter(pw, c.key)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(ew, r); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(ew.Close())
+	}()
+
+	return c.Client.UploadSession(path, pr)
+}
+
+// Download returns the decrypted contents of `name`.
+func (c *EncryptedClient) Download(name string) (io.ReadCloser, error) {
+	rc, err := c.Client.Download(name)
+	if err != nil {
+		return nil, err
+	}
+
+	er, err := NewEncryptedReader(rc, c.key)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	return &decryptedFile{er: er, rc: rc}, nil
+}
+
+// Read returns the decrypted contents of `name`. It returns ErrInvalidTag
+// if the trailing HMAC tag does not verify, even though the full plaintext
+// has by then already been read off the wire.
+func (c *EncryptedClient) Read(name string) ([]byte, error) {
+	rc, err := c.Download(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	if err := rc.Close(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Open returns an io.Reader over the decrypted contents of `name`, mirroring
+// the read side of dropy.Client.Open.
+func (c *EncryptedClient) Open(name string) io.Reader {
+	return &lazyDownload{c: c, name: name}
+}
+
+// decryptedFile pairs an EncryptedReader with the underlying ciphertext
+// io.ReadCloser so Close tears both down and surfaces a tag mismatch.
+type decryptedFile struct {
+	er *EncryptedReader
+	rc io.ReadCloser
+}
+
+func (d *decryptedFile) Read(p []byte) (int, error) {
+	return d.er.Read(p)
+}
+
+func (d *decryptedFile) Close() error {
+	err := d.er.Close()
+	if cerr := d.rc.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// lazyDownload defers the Download call until the first Read, mirroring the
+// lazy-open behavior of dropy.Client.Open.
+type lazyDownload struct {
+	c    *EncryptedClient
+	name string
+	rc   io.ReadCloser
+	err  error
+}
+
+func (l *lazyDownload) Read(p []byte) (int, error) {
+	if l.rc == nil && l.err == nil {
+		l.rc, l.err = l.c.Download(l.name)
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+	n, err := l.rc.Read(p)
+	if err == io.EOF {
+		if cerr := l.rc.Close(); cerr != nil {
+			return n, cerr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,129 @@
+package crypto
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	key, err := GenerateKey(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("the quick brown fox jumps over the lazy dog")
+
+	var stream bytes.Buffer
+	ew, err := NewEncryptedWriter(&stream, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ew.Write(plaintext); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	er, err := NewEncryptedReader(&stream, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(er)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := er.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("round trip = %q, want %q", got, plaintext)
+	}
+}
+
+func TestEncryptDecryptSmallReads(t *testing.T) {
+	key, err := GenerateKey(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := make([]byte, 100*1024)
+	for i := range plaintext {
+		plaintext[i] = byte(i % 256)
+	}
+
+	var stream bytes.Buffer
+	ew, err := NewEncryptedWriter(&stream, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ew.Write(plaintext); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	er, err := NewEncryptedReader(&stream, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	buf := make([]byte, 7) //deliberately smaller than any internal buffering
+	for {
+		n, err := er.Read(buf)
+		got.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := er.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got.Bytes(), plaintext) {
+		t.Fatalf("round trip with small reads produced %d bytes, want %d", got.Len(), len(plaintext))
+	}
+}
+
+func TestEncryptDecryptTamperedTag(t *testing.T) {
+	key, err := GenerateKey(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stream bytes.Buffer
+	ew, err := NewEncryptedWriter(&stream, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ew.Write([]byte("tamper with me")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//flip the last byte of the trailing HMAC tag
+	tampered := stream.Bytes()
+	tampered[len(tampered)-1] ^= 0xff
+
+	er, err := NewEncryptedReader(bytes.NewReader(tampered), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(er); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := er.Close(); err != ErrInvalidTag {
+		t.Fatalf("Close() = %v, want ErrInvalidTag", err)
+	}
+}
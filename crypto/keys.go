@@ -0,0 +1,56 @@
+// Package crypto provides a client-side, streaming encryption layer on top
+// of dropy.Client. Files are encrypted with AES-CTR and authenticated with
+// HMAC-SHA256 before they ever leave the machine, so Dropbox (or anyone with
+// access to the account) only ever sees ciphertext.
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// hkdfInfoEnc and hkdfInfoMAC separate the AES and HMAC subkeys derived from
+// a single master key, so compromising one never reveals the other.
+var (
+	hkdfInfoEnc = []byte("go-dropy/crypto enc")
+	hkdfInfoMAC = []byte("go-dropy/crypto mac")
+)
+
+// aesKeySize is the AES-256 key size used for the derived encryption key.
+const aesKeySize = 32
+
+// macKeySize is the key size used for the derived HMAC-SHA256 key.
+const macKeySize = 32
+
+// GenerateKey returns a random master key of length bytes, suitable for
+// passing to NewEncryptedClient, NewEncryptedReader, or NewEncryptedWriter.
+func GenerateKey(length int) ([]byte, error) {
+	if length <= 0 {
+		return nil, errors.New("dropy/crypto: key length must be positive")
+	}
+	key := make([]byte, length)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// deriveKeys splits masterKey into an independent AES key and HMAC key via
+// HKDF-SHA256.
+func deriveKeys(masterKey []byte) (encKey, macKey []byte, err error) {
+	encKey = make([]byte, aesKeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, masterKey, nil, hkdfInfoEnc), encKey); err != nil {
+		return nil, nil, err
+	}
+
+	macKey = make([]byte, macKeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, masterKey, nil, hkdfInfoMAC), macKey); err != nil {
+		return nil, nil, err
+	}
+
+	return encKey, macKey, nil
+}
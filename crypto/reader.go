@@ -0,0 +1,136 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+)
+
+// ErrInvalidTag is returned by (*EncryptedReader).Close when the trailing
+// HMAC tag does not match IV||ciphertext, meaning the stream was truncated,
+// corrupted, or not encrypted with this key.
+var ErrInvalidTag = errors.New("dropy/crypto: hmac tag mismatch")
+
+// fillSize is the chunk size EncryptedReader reads from its source at a
+// time; it has no bearing on the wire format, only on read granularity.
+const fillSize = 32 * 1024
+
+// EncryptedReader decrypts a stream produced by EncryptedWriter: it reads
+// the leading IV, then streams AES-CTR decryption as ciphertext becomes
+// available, holding back only the final tagSize bytes (which cannot be
+// told apart from ciphertext until the source is exhausted). The HMAC tag
+// is verified when Close is called.
+type EncryptedReader struct {
+	src    io.Reader
+	stream cipher.Stream
+	mac    hash.Hash
+	buf    []byte
+	eof    bool
+	tag    []byte
+}
+
+// NewEncryptedReader derives per-stream keys from masterKey via HKDF-SHA256,
+// reads the leading IV off r, and returns an EncryptedReader ready to
+// decrypt the rest of the stream.
+func NewEncryptedReader(r io.Reader, masterKey []byte) (*EncryptedReader, error) {
+	encKey, macKey, err := deriveKeys(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, ivSize)
+	if _, err := io.ReadFull(r, iv); err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+
+	return &EncryptedReader{
+		src:    r,
+		stream: cipher.NewCTR(block, iv),
+		mac:    mac,
+	}, nil
+}
+
+// fill reads more ciphertext from the source into the lookahead buffer.
+func (d *EncryptedReader) fill() error {
+	chunk := make([]byte, fillSize)
+	n, err := d.src.Read(chunk)
+	if n > 0 {
+		d.buf = append(d.buf, chunk[:n]...)
+	}
+	if err == io.EOF {
+		d.eof = true
+		return nil
+	}
+	return err
+}
+
+// Read decrypts and returns plaintext as ciphertext becomes available,
+// always holding back the final tagSize bytes of the stream as the
+// (unverified until Close) HMAC tag.
+func (d *EncryptedReader) Read(p []byte) (int, error) {
+	for len(d.buf) <= tagSize && !d.eof {
+		if err := d.fill(); err != nil {
+			return 0, err
+		}
+	}
+
+	if d.eof && d.tag == nil {
+		if len(d.buf) < tagSize {
+			return 0, io.ErrUnexpectedEOF
+		}
+		d.tag = d.buf[len(d.buf)-tagSize:]
+		d.buf = d.buf[:len(d.buf)-tagSize]
+	}
+
+	if len(d.buf) == 0 {
+		return 0, io.EOF
+	}
+
+	releasable := len(d.buf)
+	if !d.eof {
+		releasable -= tagSize
+	}
+	if releasable > len(p) {
+		releasable = len(p)
+	}
+
+	ct := d.buf[:releasable]
+	d.mac.Write(ct)
+	d.stream.XORKeyStream(p[:releasable], ct)
+	d.buf = d.buf[releasable:]
+
+	return releasable, nil
+}
+
+// Close verifies the trailing HMAC tag over IV||ciphertext and returns
+// ErrInvalidTag if it does not match. If the stream has not been fully read
+// yet, Close first reads and discards the rest of it so the tag is seen.
+func (d *EncryptedReader) Close() error {
+	if d.tag == nil {
+		//drain the rest of the stream so the tag is captured
+		buf := make([]byte, fillSize)
+		for {
+			if _, err := d.Read(buf); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	if !hmac.Equal(d.tag, d.mac.Sum(nil)) {
+		return ErrInvalidTag
+	}
+	return nil
+}
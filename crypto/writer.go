@@ -0,0 +1,89 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"hash"
+	"io"
+)
+
+// ivSize is the AES block size, and also the size of the random IV written
+// as the first ivSize bytes of every encrypted stream.
+const ivSize = aes.BlockSize
+
+// tagSize is the size of the trailing HMAC-SHA256 tag appended to every
+// encrypted stream.
+const tagSize = sha256.Size
+
+// EncryptedWriter encrypts plaintext written to it with AES-CTR and writes
+// the ciphertext to an underlying io.Writer, streaming block by block with
+// no internal buffering. The stream format is:
+//
+//	IV (16 bytes) || ciphertext || HMAC-SHA256(IV || ciphertext) (32 bytes)
+//
+// The HMAC tag is only written once Close is called, so callers must always
+// call Close to produce a verifiable stream.
+type EncryptedWriter struct {
+	w      io.Writer
+	stream cipher.Stream
+	mac    hash.Hash
+	closed bool
+}
+
+// NewEncryptedWriter derives per-stream keys from masterKey via HKDF-SHA256,
+// generates a random IV, writes it to w, and returns an EncryptedWriter
+// ready to encrypt plaintext written to it.
+func NewEncryptedWriter(w io.Writer, masterKey []byte) (*EncryptedWriter, error) {
+	encKey, macKey, err := deriveKeys(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, ivSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(iv); err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+
+	return &EncryptedWriter{
+		w:      w,
+		stream: cipher.NewCTR(block, iv),
+		mac:    mac,
+	}, nil
+}
+
+// Write encrypts p and writes the ciphertext to the underlying writer.
+func (e *EncryptedWriter) Write(p []byte) (int, error) {
+	ct := make([]byte, len(p))
+	e.stream.XORKeyStream(ct, p)
+	e.mac.Write(ct)
+
+	if _, err := e.w.Write(ct); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes the trailing HMAC tag over IV||ciphertext, finalizing the
+// stream. It is safe to call more than once.
+func (e *EncryptedWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	_, err := e.w.Write(e.mac.Sum(nil))
+	return err
+}
@@ -0,0 +1,68 @@
+package dropy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/tj/go-dropbox"
+)
+
+// hashBlockSize is the block size Dropbox uses for its content hash
+// algorithm: https://www.dropbox.com/developers/reference/content-hash
+const hashBlockSize = 4 * 1024 * 1024
+
+// ContentHash computes Dropbox's content hash of r: the input is split into
+// 4 MiB blocks, each block is hashed with SHA-256, the raw block hashes are
+// concatenated in order, and the hex-encoded SHA-256 of that concatenation
+// is returned. This matches the `content_hash` field on file metadata, so
+// it can be used to verify an upload landed intact without downloading it
+// back.
+func ContentHash(r io.Reader) (string, error) {
+	overall := sha256.New()
+	block := make([]byte, hashBlockSize)
+
+	for {
+		n, err := io.ReadFull(r, block)
+		if n > 0 {
+			sum := sha256.Sum256(block[:n])
+			overall.Write(sum[:])
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(overall.Sum(nil)), nil
+}
+
+// ContentHashFile computes ContentHash of the file at path.
+func ContentHashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return ContentHash(f)
+}
+
+// Verify compares the content hash of the local file at localPath against
+// the content hash of remotePath's metadata, returning true if they match.
+func (c *Client) Verify(localPath, remotePath string) (bool, error) {
+	localHash, err := ContentHashFile(localPath)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := c.Files.GetMetadata(&dropbox.GetMetadataInput{Path: remotePath})
+	if err != nil {
+		return false, err
+	}
+
+	return localHash == out.Metadata.ContentHash, nil
+}
@@ -0,0 +1,80 @@
+package dropy
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestContentHash(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "empty",
+			data: nil,
+			want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name: "single partial block",
+			data: []byte("Hello World"),
+			want: "42a873ac3abd02122d27e80486c6fa1ef78694e8505fcec9cbcc8a7728ba8949",
+		},
+		{
+			name: "multiple blocks with a short final block",
+			data: patternBytes(5*1024*1024 + 37),
+			want: "5d157340a1a7d1c5040c46d71a1198f2a649f27c6cdce663b8ff108717022a84",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ContentHash(bytes.NewReader(c.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("ContentHash() = %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestContentHashFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "dropy-content-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write([]byte("Hello World")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ContentHashFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "42a873ac3abd02122d27e80486c6fa1ef78694e8505fcec9cbcc8a7728ba8949"
+	if got != want {
+		t.Errorf("ContentHashFile() = %s, want %s", got, want)
+	}
+}
+
+// patternBytes returns n bytes of the repeating sequence 0..255, used to
+// build deterministic multi-block test input without depending on a fixture
+// file.
+func patternBytes(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 256)
+	}
+	return b
+}
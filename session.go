@@ -0,0 +1,118 @@
+package dropy
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/tj/go-dropbox"
+)
+
+// SessionState is a checkpoint of an in-progress upload session, suitable
+// for persisting to disk between runs or process restarts. It holds just
+// enough to resume an UploadSessionResume call: the Dropbox session id, how
+// far into the reader the session has progressed, and the chunk size and
+// target path the session was started with.
+type SessionState struct {
+	SessionID string `json:"session_id"`
+	Offset    int64  `json:"offset"`
+	ChunkSize int64  `json:"chunk_size"`
+	Path      string `json:"path"`
+}
+
+// UploadSessionResume uploads opts.Commit.Reader using an upload session
+// that can survive crashes and network drops. If state is nil a new session
+// is started, as with UploadSessionOptions. Otherwise opts.Commit.Reader is
+// discarded up to state.Offset and the session resumes from there with
+// UploadSessionAppend.
+//
+// After every successfully uploaded chunk, opts.OnProgress (if set) is
+// called with the current SessionState so the caller can checkpoint it. On
+// any append error the last-known-good SessionState is returned alongside
+// the error so the caller can retry later with UploadSessionResume.
+func (c *Client) UploadSessionResume(opts UploadSessionOptions, state *SessionState) (*SessionState, os.FileInfo, error) {
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = defaultChunkSize
+	} else if opts.ChunkSize > 150e6 {
+		opts.ChunkSize = 150e6 //cap at 150MB
+	}
+
+	var curs dropbox.UploadSessionCursor
+
+	if state == nil {
+		buf := make([]byte, opts.ChunkSize)
+		n, err := io.ReadFull(opts.Commit.Reader, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, nil, err
+		}
+
+		start, serr := c.Files.UploadSessionStart(&dropbox.UploadSessionStartInput{Reader: bytes.NewReader(buf[:n])})
+		if serr != nil {
+			return nil, nil, serr
+		}
+
+		curs = start.UploadSessionCursor
+		curs.Offset = int64(n)
+		state = &SessionState{
+			SessionID: curs.SessionID,
+			Offset:    curs.Offset,
+			ChunkSize: opts.ChunkSize,
+			Path:      opts.Commit.Path,
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(*state)
+		}
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			//reader was smaller than a single chunk, nothing left to append
+			return c.finishUploadSession(opts, curs, state)
+		}
+	} else {
+		if _, err := io.CopyN(ioutil.Discard, opts.Commit.Reader, state.Offset); err != nil && err != io.EOF {
+			return state, nil, err
+		}
+		opts.ChunkSize = state.ChunkSize
+		curs = dropbox.UploadSessionCursor{SessionID: state.SessionID, Offset: state.Offset}
+	}
+
+	for {
+		buf := make([]byte, opts.ChunkSize)
+		n, rerr := io.ReadFull(opts.Commit.Reader, buf)
+		if n == 0 {
+			break
+		}
+
+		if err := c.Files.UploadSessionAppend(&dropbox.UploadSessionAppendInput{
+			Cursor: curs,
+			Reader: bytes.NewReader(buf[:n]),
+		}); err != nil {
+			return state, nil, err
+		}
+
+		curs.Offset += int64(n)
+		state.Offset = curs.Offset
+		if opts.OnProgress != nil {
+			opts.OnProgress(*state)
+		}
+
+		if rerr == io.ErrUnexpectedEOF || rerr == io.EOF || int64(n) < opts.ChunkSize {
+			break
+		}
+		if opts.Size > 0 && curs.Offset+opts.ChunkSize > opts.Size {
+			break
+		}
+	}
+
+	return c.finishUploadSession(opts, curs, state)
+}
+
+func (c *Client) finishUploadSession(opts UploadSessionOptions, curs dropbox.UploadSessionCursor, state *SessionState) (*SessionState, os.FileInfo, error) {
+	fin, err := c.Files.UploadSessionFinish(&dropbox.UploadSessionFinishInput{
+		Cursor: curs,
+		Commit: opts.Commit,
+	})
+	if err != nil {
+		return state, nil, err
+	}
+	return state, &FileInfo{&fin.UploadOutput.Metadata}, nil
+}
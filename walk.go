@@ -0,0 +1,97 @@
+package dropy
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tj/go-dropbox"
+)
+
+// Walk traverses the Dropbox tree rooted at `root`, calling fn for each
+// entry with its full display path, in the style of filepath.Walk. It
+// paginates internally via Files.ListFolder and Files.ListFolderContinue, so
+// callers never have to hand-roll cursors.
+//
+// If fn returns filepath.SkipDir for a directory entry, Walk skips all
+// descendants of that directory. Any other non-nil error returned by fn
+// stops the walk and is returned by Walk.
+func (c *Client) Walk(root string, fn func(path string, info os.FileInfo, err error) error) error {
+	return c.WalkN(root, -1, fn)
+}
+
+// WalkN is like Walk but stops after at most n entries have been passed to
+// fn. n <= 0 means no limit.
+func (c *Client) WalkN(root string, n int, fn func(path string, info os.FileInfo, err error) error) error {
+	var cursor string
+	var skipped []string
+	var count int
+	lastPath := root
+
+	for {
+		var out *dropbox.ListFolderOutput
+		var err error
+
+		if cursor == "" {
+			out, err = c.Files.ListFolder(&dropbox.ListFolderInput{Path: root, Recursive: true})
+		} else {
+			out, err = c.Files.ListFolderContinue(&dropbox.ListFolderContinueInput{cursor})
+		}
+
+		if err != nil {
+			//per filepath.Walk's contract, fn can inspect the error and
+			//return nil to have the walk resume; since there's no next
+			//page to resume onto after a failed list call, resuming here
+			//means stopping cleanly rather than re-issuing the same
+			//failing request
+			return fn(lastPath, nil, err)
+		}
+		cursor = out.Cursor
+
+		for _, ent := range out.Entries {
+			info := os.FileInfo(&FileInfo{ent})
+			p := ent.PathDisplay
+			lastPath = p
+
+			if isUnderAny(p, skipped) {
+				continue
+			}
+
+			err := fn(p, info, nil)
+			count++
+			if err != nil {
+				if err != filepath.SkipDir {
+					return err
+				}
+				if info.IsDir() {
+					skipped = append(skipped, p)
+				} else {
+					//per filepath.Walk's contract, SkipDir on a
+					//non-directory skips the remaining files in its
+					//containing directory
+					skipped = append(skipped, path.Dir(p))
+				}
+			}
+
+			if n > 0 && count >= n {
+				return nil
+			}
+		}
+
+		if !out.HasMore {
+			return nil
+		}
+	}
+}
+
+// isUnderAny reports whether path is equal to, or a descendant of, any of
+// the given directory prefixes.
+func isUnderAny(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
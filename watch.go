@@ -0,0 +1,193 @@
+package dropy
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tj/go-dropbox"
+)
+
+// ChangeType describes the kind of change a ChangeEvent represents.
+type ChangeType int
+
+// Change types emitted by Watch.
+const (
+	Added ChangeType = iota
+	Modified
+	Deleted
+)
+
+// ChangeEvent describes a single change observed by Watch. Info is nil for
+// Deleted events, since Dropbox does not return metadata for deleted paths.
+type ChangeEvent struct {
+	Path string
+	Type ChangeType
+	Info os.FileInfo
+}
+
+// Backoff controls how Watch waits before retrying after a longpoll
+// `reset` response, which tells the caller to drop its cursor and re-list
+// from scratch.
+type Backoff struct {
+	// Min is the delay before the first retry. Defaults to 1 second.
+	Min time.Duration
+	// Max is the maximum delay between retries. Defaults to 30 seconds.
+	Max time.Duration
+}
+
+func (b Backoff) min() time.Duration {
+	if b.Min <= 0 {
+		return time.Second
+	}
+	return b.Min
+}
+
+func (b Backoff) max() time.Duration {
+	if b.Max <= 0 {
+		return 30 * time.Second
+	}
+	return b.Max
+}
+
+// WatchOptions configures Client.Watch.
+type WatchOptions struct {
+	// Timeout is the longpoll timeout in seconds, clamped to the range
+	// Dropbox accepts (30-480). Defaults to 30.
+	Timeout uint64
+	// Backoff controls retry delays after a longpoll `reset` response.
+	Backoff Backoff
+}
+
+// Watch emits filesystem change events for `path`. It lists the folder once
+// to obtain a starting cursor, then longpolls for changes, translating each
+// changed entry into a ChangeEvent on the returned channel. Call the
+// returned cancel func to stop the watch and close the channel.
+func (c *Client) Watch(path string, opts WatchOptions) (<-chan ChangeEvent, func(), error) {
+	timeout := opts.Timeout
+	if timeout < 30 {
+		timeout = 30
+	} else if timeout > 480 {
+		timeout = 480
+	}
+
+	out, err := c.Files.ListFolder(&dropbox.ListFolderInput{Path: path, Recursive: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool, len(out.Entries))
+	for _, ent := range out.Entries {
+		seen[ent.PathDisplay] = true
+	}
+
+	events := make(chan ChangeEvent)
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() { once.Do(func() { close(done) }) }
+
+	go c.watchLoop(path, out.Cursor, timeout, opts.Backoff, seen, events, done)
+
+	return events, cancel, nil
+}
+
+func (c *Client) watchLoop(path, cursor string, timeout uint64, backoff Backoff, seen map[string]bool, events chan<- ChangeEvent, done <-chan struct{}) {
+	defer close(events)
+
+	delay := backoff.min()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		lp, err := c.Files.ListFolderLongpoll(&dropbox.ListFolderLongpollInput{
+			Cursor:  cursor,
+			Timeout: timeout,
+		})
+		if err != nil {
+			//transient network blips shouldn't end the watch; back off and
+			//retry the same longpoll call
+			select {
+			case <-time.After(delay):
+			case <-done:
+				return
+			}
+			delay *= 2
+			if delay > backoff.max() {
+				delay = backoff.max()
+			}
+			continue
+		}
+		delay = backoff.min()
+
+		if lp.Backoff > 0 {
+			time.Sleep(time.Duration(lp.Backoff) * time.Second)
+		}
+
+		if !lp.Changes {
+			continue
+		}
+
+		for {
+			cont, err := c.Files.ListFolderContinue(&dropbox.ListFolderContinueInput{cursor})
+			if err != nil {
+				//a reset error means the cursor is no longer valid; re-list
+				//from scratch after a backoff, retrying the re-list itself
+				//under the same backoff policy rather than ending the watch
+				//on one transient failure
+				for {
+					select {
+					case <-time.After(delay):
+					case <-done:
+						return
+					}
+					delay *= 2
+					if delay > backoff.max() {
+						delay = backoff.max()
+					}
+
+					out, rerr := c.Files.ListFolder(&dropbox.ListFolderInput{Path: path, Recursive: true})
+					if rerr == nil {
+						cursor = out.Cursor
+						break
+					}
+				}
+				break
+			}
+
+			delay = backoff.min()
+			cursor = cont.Cursor
+
+			for _, ent := range cont.Entries {
+				evt := ChangeEvent{Path: ent.PathDisplay}
+
+				if ent.Tag == "deleted" {
+					evt.Type = Deleted
+					evt.Info = nil
+					delete(seen, ent.PathDisplay)
+				} else {
+					evt.Info = &FileInfo{ent}
+					if seen[ent.PathDisplay] {
+						evt.Type = Modified
+					} else {
+						evt.Type = Added
+					}
+					seen[ent.PathDisplay] = true
+				}
+
+				select {
+				case events <- evt:
+				case <-done:
+					return
+				}
+			}
+
+			if !cont.HasMore {
+				break
+			}
+		}
+	}
+}